@@ -0,0 +1,78 @@
+package git
+
+import "errors"
+
+// ErrUnsupported is returned by a Backend implementation for operations it
+// cannot perform, so callers can fall back to another backend.
+var ErrUnsupported = errors.New("operation not supported by this git backend")
+
+// Backend is the set of git operations polecat and swarm need, abstracted
+// so an exec-based implementation and a pure-Go one can be swapped in.
+type Backend interface {
+	// Clone clones url into dir.
+	Clone(url, dir string) error
+
+	// Open returns a Backend of the same kind rooted at dir.
+	Open(dir string) (Backend, error)
+
+	// CreateBranch creates a new branch without checking it out.
+	CreateBranch(name string) error
+
+	// Checkout switches to the named branch.
+	Checkout(name string) error
+
+	// HasUncommittedChanges reports whether the working tree has local modifications.
+	HasUncommittedChanges() (bool, error)
+
+	// Fetch fetches refs from remote.
+	Fetch(remote string) error
+
+	// Push pushes branch to remote.
+	Push(remote, branch string) error
+
+	// CurrentSHA returns the commit hash HEAD points to.
+	CurrentSHA() (string, error)
+
+	// WorktreeAdd creates a new worktree at worktreePath on a new branch.
+	WorktreeAdd(worktreePath, branch string) error
+
+	// WorktreeRemove removes the worktree at worktreePath.
+	WorktreeRemove(worktreePath string, force bool) error
+
+	// WorktreePrune removes stale administrative files for missing worktrees.
+	WorktreePrune() error
+
+	// WorktreeList returns the porcelain listing of registered worktrees.
+	WorktreeList() (string, error)
+}
+
+// Kind selects which Backend implementation a rig uses.
+type Kind string
+
+const (
+	// KindExec shells out to the git binary. It supports every Backend
+	// operation, including the worktree ones. Backend has no filter-branch
+	// operation.
+	KindExec Kind = "exec"
+
+	// KindGoGit uses the pure-Go go-git library. It has no fork overhead and
+	// supports in-memory repositories for hermetic tests, but does not
+	// support linked worktrees.
+	KindGoGit Kind = "go-git"
+)
+
+// NewBackend constructs a Backend of the given kind rooted at dir. An empty
+// kind defaults to KindExec.
+func NewBackend(kind Kind, dir string) (Backend, error) {
+	switch kind {
+	case "", KindExec:
+		return NewGit(dir), nil
+	case KindGoGit:
+		return NewGoGitBackend(dir), nil
+	default:
+		return nil, errors.New("unknown git backend kind: " + string(kind))
+	}
+}
+
+var _ Backend = (*Git)(nil)
+var _ Backend = (*GoGitBackend)(nil)
@@ -0,0 +1,131 @@
+// Package git wraps the git CLI for the operations polecat and swarm need.
+package git
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// Git runs git commands against a single working directory by shelling out
+// to the git binary. It implements Backend.
+type Git struct {
+	dir string
+}
+
+// NewGit returns a Git wrapper rooted at dir.
+func NewGit(dir string) *Git {
+	return &Git{dir: dir}
+}
+
+// Open returns a Git wrapper rooted at dir.
+func (g *Git) Open(dir string) (Backend, error) {
+	return NewGit(dir), nil
+}
+
+// run executes git with args in g's directory and returns combined stdout+stderr.
+func (g *Git) run(args ...string) (string, error) {
+	cmd := exec.Command("git", args...)
+	cmd.Dir = g.dir
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	if err := cmd.Run(); err != nil {
+		return out.String(), fmt.Errorf("git %s: %w: %s", strings.Join(args, " "), err, out.String())
+	}
+
+	return out.String(), nil
+}
+
+// Clone clones url into dir.
+func (g *Git) Clone(url, dir string) error {
+	cmd := exec.Command("git", "clone", url, dir)
+
+	var out bytes.Buffer
+	cmd.Stdout = &out
+	cmd.Stderr = &out
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("git clone: %w: %s", err, out.String())
+	}
+
+	return nil
+}
+
+// CreateBranch creates a new branch without checking it out.
+func (g *Git) CreateBranch(name string) error {
+	_, err := g.run("branch", name)
+	return err
+}
+
+// Checkout switches to the named branch.
+func (g *Git) Checkout(name string) error {
+	_, err := g.run("checkout", name)
+	return err
+}
+
+// HasUncommittedChanges reports whether the working tree has local modifications.
+func (g *Git) HasUncommittedChanges() (bool, error) {
+	out, err := g.run("status", "--porcelain")
+	if err != nil {
+		return false, err
+	}
+	return strings.TrimSpace(out) != "", nil
+}
+
+// WorktreeAdd creates a new worktree at worktreePath on a new branch, run
+// from the canonical checkout.
+func (g *Git) WorktreeAdd(worktreePath, branch string) error {
+	_, err := g.run("worktree", "add", "-b", branch, worktreePath)
+	return err
+}
+
+// WorktreeRemove removes the worktree at worktreePath. If force is true,
+// it removes the worktree even if it is dirty or locked.
+func (g *Git) WorktreeRemove(worktreePath string, force bool) error {
+	args := []string{"worktree", "remove"}
+	if force {
+		// git needs --force twice to remove a locked worktree.
+		args = append(args, "--force", "--force")
+	}
+	args = append(args, worktreePath)
+
+	_, err := g.run(args...)
+	return err
+}
+
+// WorktreePrune removes stale administrative files under .git/worktrees for
+// worktrees whose directories no longer exist.
+func (g *Git) WorktreePrune() error {
+	_, err := g.run("worktree", "prune")
+	return err
+}
+
+// WorktreeList returns the porcelain listing of registered worktrees.
+func (g *Git) WorktreeList() (string, error) {
+	return g.run("worktree", "list", "--porcelain")
+}
+
+// Fetch fetches refs from remote.
+func (g *Git) Fetch(remote string) error {
+	_, err := g.run("fetch", remote)
+	return err
+}
+
+// Push pushes branch to remote.
+func (g *Git) Push(remote, branch string) error {
+	_, err := g.run("push", remote, branch)
+	return err
+}
+
+// CurrentSHA returns the commit hash HEAD points to.
+func (g *Git) CurrentSHA() (string, error) {
+	out, err := g.run("rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(out), nil
+}
@@ -0,0 +1,300 @@
+package git
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/go-git/go-billy/v5"
+	"github.com/go-git/go-billy/v5/memfs"
+	"github.com/go-git/go-billy/v5/osfs"
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/config"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/cache"
+	"github.com/go-git/go-git/v5/plumbing/object"
+	"github.com/go-git/go-git/v5/storage"
+	"github.com/go-git/go-git/v5/storage/filesystem"
+	"github.com/go-git/go-git/v5/storage/memory"
+)
+
+// gitDirName is the administrative subdirectory go-git stores repository
+// data in for on-disk, non-bare repositories, matching git's own convention.
+const gitDirName = ".git"
+
+// GoGitBackend implements Backend using the pure-Go go-git library instead
+// of shelling out to the git binary. It does not support linked worktrees;
+// rigs that need those should select KindExec instead.
+type GoGitBackend struct {
+	dir    string
+	fs     billy.Filesystem
+	storer storage.Storer
+	repo   *gogit.Repository
+
+	// memory is true when this backend wraps an in-memory repository built
+	// by NewGoGitBackendMemory, rather than an on-disk one. Clone and Open
+	// check it so a memory-backed Manager never falls back to real disk.
+	memory bool
+}
+
+// NewGoGitBackend returns a GoGitBackend rooted at dir on the real filesystem.
+// The repository is opened lazily by the first operation that needs it.
+func NewGoGitBackend(dir string) *GoGitBackend {
+	return &GoGitBackend{dir: dir}
+}
+
+// NewGoGitBackendMemory returns a GoGitBackend backed entirely by an
+// in-memory filesystem and object store, for hermetic tests that should
+// not touch real disk or require the git binary in $PATH. Its Clone and
+// Open ignore the destination/dir argument and reuse this same in-memory
+// repository, so it can be handed to polecat.NewManager directly and drive
+// Add/Remove/AssignIssue without ever touching a directory on disk.
+//
+// Because every Open shares one repository, this is only suitable for
+// tests that drive a single polecat at a time: a second polecat's
+// CreateBranch/Checkout would move the same repo's HEAD out from under the
+// first. Tests that need several independent polecats should still use
+// one GoGitBackendMemory per polecat, or a hand-rolled fake.
+func NewGoGitBackendMemory() (*GoGitBackend, error) {
+	fs := memfs.New()
+	storer := memory.NewStorage()
+
+	repo, err := gogit.Init(storer, fs)
+	if err != nil {
+		return nil, fmt.Errorf("initializing in-memory repo: %w", err)
+	}
+
+	return &GoGitBackend{fs: fs, storer: storer, repo: repo, memory: true}, nil
+}
+
+// SeedCommit commits path=content into b's worktree, for tests that need an
+// in-memory repository with history before driving it through
+// polecat.Manager: CreateBranch resolves HEAD, which doesn't exist until a
+// repository has at least one commit.
+func (b *GoGitBackend) SeedCommit(path, content string) error {
+	wt, err := b.repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("getting worktree: %w", err)
+	}
+
+	f, err := wt.Filesystem.Create(path)
+	if err != nil {
+		return fmt.Errorf("creating %s: %w", path, err)
+	}
+	if _, err := f.Write([]byte(content)); err != nil {
+		f.Close()
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("closing %s: %w", path, err)
+	}
+
+	if _, err := wt.Add(path); err != nil {
+		return fmt.Errorf("staging %s: %w", path, err)
+	}
+
+	sig := &object.Signature{Name: "gastown", Email: "gastown@localhost", When: time.Now()}
+	if _, err := wt.Commit("seed "+path, &gogit.CommitOptions{Author: sig}); err != nil {
+		return fmt.Errorf("committing %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// dotGitStorage returns the worktree filesystem rooted at dir, and a storer
+// for the .git subdirectory within it, the same layout gogit.PlainOpen and
+// gogit.PlainInit use for on-disk, non-bare repositories.
+func dotGitStorage(dir string) (billy.Filesystem, storage.Storer, error) {
+	fs := osfs.New(dir)
+
+	dot, err := fs.Chroot(gitDirName)
+	if err != nil {
+		return nil, nil, fmt.Errorf("chrooting to %s: %w", gitDirName, err)
+	}
+
+	return fs, filesystem.NewStorage(dot, cache.NewObjectLRUDefault()), nil
+}
+
+// open lazily opens the on-disk repository at dir.
+func (b *GoGitBackend) open() error {
+	if b.repo != nil {
+		return nil
+	}
+
+	fs, storer, err := dotGitStorage(b.dir)
+	if err != nil {
+		return err
+	}
+
+	repo, err := gogit.Open(storer, fs)
+	if err != nil {
+		return fmt.Errorf("opening repo: %w", err)
+	}
+
+	b.fs = fs
+	b.storer = storer
+	b.repo = repo
+	return nil
+}
+
+// Clone clones url into dir. It does not mutate b: callers that need a
+// handle on the clone should Open(dir) afterwards, the same way the exec
+// backend works. This keeps a single Backend safe to share across
+// concurrent Clone calls into different directories (e.g. Manager.Add
+// creating several polecats at once).
+//
+// If b already wraps an in-memory repository (built by
+// NewGoGitBackendMemory), there is no on-disk destination to clone into:
+// dir is ignored and Clone is a no-op, since the existing in-memory repo
+// already stands in as the clone.
+func (b *GoGitBackend) Clone(url, dir string) error {
+	if b.memory {
+		return nil
+	}
+
+	fs, storer, err := dotGitStorage(dir)
+	if err != nil {
+		return err
+	}
+
+	if _, err := gogit.Clone(storer, fs, &gogit.CloneOptions{URL: url}); err != nil {
+		return fmt.Errorf("go-git clone: %w", err)
+	}
+
+	return nil
+}
+
+// Open returns a GoGitBackend rooted at dir. If b already wraps an
+// in-memory repository, dir is ignored and b itself is returned: an
+// in-memory backend has no concept of separate on-disk directories.
+func (b *GoGitBackend) Open(dir string) (Backend, error) {
+	if b.memory {
+		return b, nil
+	}
+
+	opened := NewGoGitBackend(dir)
+	if err := opened.open(); err != nil {
+		return nil, err
+	}
+	return opened, nil
+}
+
+// CreateBranch creates a new branch at HEAD without checking it out.
+func (b *GoGitBackend) CreateBranch(name string) error {
+	if err := b.open(); err != nil {
+		return err
+	}
+
+	head, err := b.repo.Head()
+	if err != nil {
+		return fmt.Errorf("resolving HEAD: %w", err)
+	}
+
+	ref := plumbing.NewHashReference(plumbing.NewBranchReferenceName(name), head.Hash())
+	if err := b.repo.Storer.SetReference(ref); err != nil {
+		return fmt.Errorf("creating branch %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// Checkout switches to the named branch.
+func (b *GoGitBackend) Checkout(name string) error {
+	if err := b.open(); err != nil {
+		return err
+	}
+
+	wt, err := b.repo.Worktree()
+	if err != nil {
+		return fmt.Errorf("getting worktree: %w", err)
+	}
+
+	if err := wt.Checkout(&gogit.CheckoutOptions{Branch: plumbing.NewBranchReferenceName(name)}); err != nil {
+		return fmt.Errorf("checking out %s: %w", name, err)
+	}
+
+	return nil
+}
+
+// HasUncommittedChanges reports whether the working tree has local modifications.
+func (b *GoGitBackend) HasUncommittedChanges() (bool, error) {
+	if err := b.open(); err != nil {
+		return false, err
+	}
+
+	wt, err := b.repo.Worktree()
+	if err != nil {
+		return false, fmt.Errorf("getting worktree: %w", err)
+	}
+
+	status, err := wt.Status()
+	if err != nil {
+		return false, fmt.Errorf("getting status: %w", err)
+	}
+
+	return !status.IsClean(), nil
+}
+
+// Fetch fetches refs from remote.
+func (b *GoGitBackend) Fetch(remote string) error {
+	if err := b.open(); err != nil {
+		return err
+	}
+
+	err := b.repo.Fetch(&gogit.FetchOptions{RemoteName: remote})
+	if err != nil && err != gogit.NoErrAlreadyUpToDate {
+		return fmt.Errorf("fetching %s: %w", remote, err)
+	}
+	return nil
+}
+
+// Push pushes branch to remote.
+func (b *GoGitBackend) Push(remote, branch string) error {
+	if err := b.open(); err != nil {
+		return err
+	}
+
+	refSpec := fmt.Sprintf("refs/heads/%s:refs/heads/%s", branch, branch)
+	err := b.repo.Push(&gogit.PushOptions{
+		RemoteName: remote,
+		RefSpecs:   []config.RefSpec{config.RefSpec(refSpec)},
+	})
+	if err != nil && err != gogit.NoErrAlreadyUpToDate {
+		return fmt.Errorf("pushing %s to %s: %w", branch, remote, err)
+	}
+	return nil
+}
+
+// CurrentSHA returns the commit hash HEAD points to.
+func (b *GoGitBackend) CurrentSHA() (string, error) {
+	if err := b.open(); err != nil {
+		return "", err
+	}
+
+	head, err := b.repo.Head()
+	if err != nil {
+		return "", fmt.Errorf("resolving HEAD: %w", err)
+	}
+
+	return head.Hash().String(), nil
+}
+
+// WorktreeAdd is unsupported: go-git v5 has no support for linked worktrees.
+// Rigs that need worktree-backed polecats must select KindExec.
+func (b *GoGitBackend) WorktreeAdd(worktreePath, branch string) error {
+	return fmt.Errorf("go-git backend: worktree add: %w", ErrUnsupported)
+}
+
+// WorktreeRemove is unsupported, see WorktreeAdd.
+func (b *GoGitBackend) WorktreeRemove(worktreePath string, force bool) error {
+	return fmt.Errorf("go-git backend: worktree remove: %w", ErrUnsupported)
+}
+
+// WorktreePrune is unsupported, see WorktreeAdd.
+func (b *GoGitBackend) WorktreePrune() error {
+	return fmt.Errorf("go-git backend: worktree prune: %w", ErrUnsupported)
+}
+
+// WorktreeList is unsupported, see WorktreeAdd.
+func (b *GoGitBackend) WorktreeList() (string, error) {
+	return "", fmt.Errorf("go-git backend: worktree list: %w", ErrUnsupported)
+}
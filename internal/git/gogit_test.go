@@ -0,0 +1,217 @@
+package git
+
+import (
+	"errors"
+	"os/exec"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	gogit "github.com/go-git/go-git/v5"
+	"github.com/go-git/go-git/v5/plumbing"
+	"github.com/go-git/go-git/v5/plumbing/object"
+)
+
+// commitFile writes path=content into b's worktree and commits it, returning
+// the new commit's hash. It drives b.repo directly rather than going through
+// Backend, since constructing a commit isn't part of the interface.
+func commitFile(t *testing.T, b *GoGitBackend, path, content string) plumbing.Hash {
+	t.Helper()
+
+	wt, err := b.repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree: %v", err)
+	}
+
+	f, err := wt.Filesystem.Create(path)
+	if err != nil {
+		t.Fatalf("creating %s: %v", path, err)
+	}
+	if _, err := f.Write([]byte(content)); err != nil {
+		t.Fatalf("writing %s: %v", path, err)
+	}
+	if err := f.Close(); err != nil {
+		t.Fatalf("closing %s: %v", path, err)
+	}
+
+	if _, err := wt.Add(path); err != nil {
+		t.Fatalf("Add(%s): %v", path, err)
+	}
+
+	sig := &object.Signature{Name: "Test", Email: "test@example.com", When: time.Now()}
+	hash, err := wt.Commit("commit "+path, &gogit.CommitOptions{Author: sig})
+	if err != nil {
+		t.Fatalf("Commit: %v", err)
+	}
+	return hash
+}
+
+// TestGoGitBackendMemory_CreateBranchAndCurrentSHA drives CreateBranch,
+// Checkout and CurrentSHA entirely against an in-memory repository, with no
+// real disk or git binary involved.
+func TestGoGitBackendMemory_CreateBranchAndCurrentSHA(t *testing.T) {
+	b, err := NewGoGitBackendMemory()
+	if err != nil {
+		t.Fatalf("NewGoGitBackendMemory: %v", err)
+	}
+
+	want := commitFile(t, b, "README.md", "hello\n")
+
+	if err := b.CreateBranch("polecat/p1"); err != nil {
+		t.Fatalf("CreateBranch: %v", err)
+	}
+	if err := b.Checkout("polecat/p1"); err != nil {
+		t.Fatalf("Checkout: %v", err)
+	}
+
+	got, err := b.CurrentSHA()
+	if err != nil {
+		t.Fatalf("CurrentSHA: %v", err)
+	}
+	if got != want.String() {
+		t.Errorf("CurrentSHA() = %s, want %s", got, want)
+	}
+}
+
+// TestGoGitBackendMemory_HasUncommittedChanges drives HasUncommittedChanges
+// against an in-memory repository before and after an uncommitted edit.
+func TestGoGitBackendMemory_HasUncommittedChanges(t *testing.T) {
+	b, err := NewGoGitBackendMemory()
+	if err != nil {
+		t.Fatalf("NewGoGitBackendMemory: %v", err)
+	}
+
+	commitFile(t, b, "README.md", "hello\n")
+
+	clean, err := b.HasUncommittedChanges()
+	if err != nil {
+		t.Fatalf("HasUncommittedChanges: %v", err)
+	}
+	if clean {
+		t.Error("HasUncommittedChanges() = true right after commit, want false")
+	}
+
+	wt, err := b.repo.Worktree()
+	if err != nil {
+		t.Fatalf("Worktree: %v", err)
+	}
+	f, err := wt.Filesystem.Create("README.md")
+	if err != nil {
+		t.Fatalf("rewriting README.md: %v", err)
+	}
+	if _, err := f.Write([]byte("changed\n")); err != nil {
+		t.Fatalf("writing README.md: %v", err)
+	}
+	f.Close()
+
+	dirty, err := b.HasUncommittedChanges()
+	if err != nil {
+		t.Fatalf("HasUncommittedChanges: %v", err)
+	}
+	if !dirty {
+		t.Error("HasUncommittedChanges() = false after an uncommitted edit, want true")
+	}
+}
+
+// TestGoGitBackendMemory_WorktreeOpsUnsupported checks that every worktree
+// method reports ErrUnsupported instead of panicking or silently no-op'ing,
+// so callers like polecat.Manager can detect the backend can't do worktrees.
+func TestGoGitBackendMemory_WorktreeOpsUnsupported(t *testing.T) {
+	b, err := NewGoGitBackendMemory()
+	if err != nil {
+		t.Fatalf("NewGoGitBackendMemory: %v", err)
+	}
+
+	if err := b.WorktreeAdd("/tmp/whatever", "branch"); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("WorktreeAdd error = %v, want ErrUnsupported", err)
+	}
+	if err := b.WorktreeRemove("/tmp/whatever", true); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("WorktreeRemove error = %v, want ErrUnsupported", err)
+	}
+	if err := b.WorktreePrune(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("WorktreePrune error = %v, want ErrUnsupported", err)
+	}
+	if _, err := b.WorktreeList(); !errors.Is(err, ErrUnsupported) {
+		t.Errorf("WorktreeList error = %v, want ErrUnsupported", err)
+	}
+}
+
+// initSourceRepo creates a tiny on-disk git repository with one commit,
+// using the real git binary as a test fixture, and returns its path as a
+// file:// URL suitable for Clone.
+func initSourceRepo(t *testing.T) string {
+	t.Helper()
+
+	dir := t.TempDir()
+	run := func(args ...string) {
+		cmd := exec.Command("git", args...)
+		cmd.Dir = dir
+		cmd.Env = append(cmd.Env,
+			"GIT_AUTHOR_NAME=Test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=Test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %v: %v: %s", args, err, out)
+		}
+	}
+
+	run("init", "-b", "main")
+	if err := exec.Command("sh", "-c", "echo hello > "+filepath.Join(dir, "README.md")).Run(); err != nil {
+		t.Fatalf("seeding README.md: %v", err)
+	}
+	run("add", "README.md")
+	run("commit", "-m", "initial commit")
+
+	return "file://" + dir
+}
+
+// TestGoGitBackend_CloneDoesNotMutateReceiver reproduces the scenario
+// Manager.Add hits when several polecats are created at once: many goroutines
+// calling Clone on the *same* shared git.Backend, into different
+// directories. Clone must not stash per-call state (dir/fs/storer/repo) on
+// the receiver, or concurrent clones race each other and callers that later
+// Open() the wrong directory's handle. Run with -race to catch a regression.
+func TestGoGitBackend_CloneDoesNotMutateReceiver(t *testing.T) {
+	srcA := initSourceRepo(t)
+	srcB := initSourceRepo(t)
+
+	dstA := filepath.Join(t.TempDir(), "a")
+	dstB := filepath.Join(t.TempDir(), "b")
+
+	shared := NewGoGitBackend("")
+
+	var wg sync.WaitGroup
+	errs := make(chan error, 2)
+	clone := func(url, dir string) {
+		defer wg.Done()
+		if err := shared.Clone(url, dir); err != nil {
+			errs <- err
+		}
+	}
+
+	wg.Add(2)
+	go clone(srcA, dstA)
+	go clone(srcB, dstB)
+	wg.Wait()
+	close(errs)
+
+	for err := range errs {
+		t.Fatalf("Clone: %v", err)
+	}
+
+	// The whole point of the fix: a shared backend used for concurrent
+	// clones into different directories must come out with its own fields
+	// untouched, not pointed at whichever clone happened to finish last.
+	if shared.dir != "" || shared.fs != nil || shared.storer != nil || shared.repo != nil {
+		t.Errorf("shared backend was mutated by Clone: dir=%q fs=%v storer=%v repo=%v",
+			shared.dir, shared.fs, shared.storer, shared.repo)
+	}
+
+	// Each destination should be an independent, valid clone on disk.
+	for _, dst := range []string{dstA, dstB} {
+		out, err := exec.Command("git", "-C", dst, "log", "--oneline").CombinedOutput()
+		if err != nil {
+			t.Errorf("git log in %s: %v: %s", dst, err, out)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+package polecat
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+)
+
+// fakeClock lets tests control what m.now() returns without sleeping.
+type fakeClock struct {
+	mu sync.Mutex
+	t  time.Time
+}
+
+func (c *fakeClock) now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.t
+}
+
+func (c *fakeClock) advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.t = c.t.Add(d)
+}
+
+func TestReaperLeavesFreshHeartbeatAlone(t *testing.T) {
+	m := newTestManager(t, StateActive)
+	clock := &fakeClock{t: time.Now()}
+	m.now = clock.now
+
+	if err := m.AssignIssue("p", "issue-1"); err != nil {
+		t.Fatalf("AssignIssue: %v", err)
+	}
+	if err := m.Heartbeat("p"); err != nil {
+		t.Fatalf("Heartbeat: %v", err)
+	}
+
+	clock.advance(30 * time.Second)
+	m.reapOnce(time.Minute)
+
+	p, err := m.Get("p")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if p.State != StateWorking {
+		t.Errorf("state = %q, want %q (fresh heartbeat should not be reaped)", p.State, StateWorking)
+	}
+}
+
+func TestReaperFlipsStaleWorkingPolecatToStuckOnce(t *testing.T) {
+	m := newTestManager(t, StateActive)
+	clock := &fakeClock{t: time.Now()}
+	m.now = clock.now
+
+	if err := m.AssignIssue("p", "issue-1"); err != nil {
+		t.Fatalf("AssignIssue: %v", err)
+	}
+	if err := m.Heartbeat("p"); err != nil {
+		t.Fatalf("Heartbeat: %v", err)
+	}
+
+	var stuckCount int
+	m.OnTransition(func(p *Polecat, from, to State, event string) {
+		if event == EventStuck {
+			stuckCount++
+		}
+	})
+
+	clock.advance(2 * time.Minute)
+	m.reapOnce(time.Minute)
+	m.reapOnce(time.Minute) // second scan should be a no-op: already stuck
+
+	p, err := m.Get("p")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if p.State != StateStuck {
+		t.Errorf("state = %q, want %q", p.State, StateStuck)
+	}
+	if stuckCount != 1 {
+		t.Errorf("stuck hook fired %d times, want 1", stuckCount)
+	}
+}
+
+func TestClearIssueClearsHeartbeat(t *testing.T) {
+	m := newTestManager(t, StateActive)
+	clock := &fakeClock{t: time.Now()}
+	m.now = clock.now
+
+	if err := m.AssignIssue("p", "issue-1"); err != nil {
+		t.Fatalf("AssignIssue: %v", err)
+	}
+	if err := m.Heartbeat("p"); err != nil {
+		t.Fatalf("Heartbeat: %v", err)
+	}
+	if err := m.MarkDone("p"); err != nil {
+		t.Fatalf("MarkDone: %v", err)
+	}
+	if err := m.ClearIssue("p"); err != nil {
+		t.Fatalf("ClearIssue: %v", err)
+	}
+
+	p, err := m.Get("p")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !p.LastHeartbeat.IsZero() {
+		t.Errorf("LastHeartbeat = %v, want zero after ClearIssue", p.LastHeartbeat)
+	}
+}
+
+func TestReaperStopsOnContextCancel(t *testing.T) {
+	m := newTestManager(t, StateIdle)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan struct{})
+	go func() {
+		m.Reaper(ctx, time.Millisecond, time.Minute)
+		close(done)
+	}()
+
+	cancel()
+	select {
+	case <-done:
+	case <-time.After(time.Second):
+		t.Fatal("Reaper did not exit after context cancellation")
+	}
+}
@@ -0,0 +1,35 @@
+package polecat
+
+import (
+	"fmt"
+	"os"
+)
+
+// stateLock is an OS-level advisory lock on a polecat's state.json.lock
+// file, held for the duration of a read-modify-write cycle so concurrent
+// callers (the swarm scheduler, a CLI command, ...) can't race each other.
+type stateLock struct {
+	f *os.File
+}
+
+// lockState acquires an exclusive advisory lock on name's state.json.lock,
+// creating the lock file if necessary. The caller must call Unlock when done.
+func (m *Manager) lockState(name string) (*stateLock, error) {
+	f, err := os.OpenFile(m.stateFile(name)+".lock", os.O_CREATE|os.O_RDWR, 0644)
+	if err != nil {
+		return nil, fmt.Errorf("opening lock file: %w", err)
+	}
+
+	if err := flock(f); err != nil {
+		f.Close()
+		return nil, fmt.Errorf("locking state: %w", err)
+	}
+
+	return &stateLock{f: f}, nil
+}
+
+// Unlock releases the lock and closes the underlying file.
+func (l *stateLock) Unlock() error {
+	defer l.f.Close()
+	return funlock(l.f)
+}
@@ -1,11 +1,13 @@
 package polecat
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
 	"time"
 
 	"github.com/steveyegge/gastown/internal/git"
@@ -21,18 +23,38 @@ var (
 
 // Manager handles polecat lifecycle.
 type Manager struct {
-	rig *rig.Rig
-	git *git.Git
+	rig   *rig.Rig
+	git   git.Backend
+	mode  Mode
+	hooks []TransitionHook
+	now   func() time.Time
 }
 
-// NewManager creates a new polecat manager.
-func NewManager(r *rig.Rig, g *git.Git) *Manager {
+// NewManager creates a new polecat manager that clones the rig for each polecat.
+func NewManager(r *rig.Rig, g git.Backend) *Manager {
+	return NewManagerWithMode(r, g, ModeClone)
+}
+
+// NewManagerWithMode creates a new polecat manager using the given materialization mode.
+func NewManagerWithMode(r *rig.Rig, g git.Backend, mode Mode) *Manager {
 	return &Manager{
-		rig: r,
-		git: g,
+		rig:  r,
+		git:  g,
+		mode: mode,
+		now:  time.Now,
 	}
 }
 
+// NewManagerForRig creates a polecat manager using the git.Backend selected
+// by the rig's GitBackend config, rooted at the rig's canonical checkout.
+func NewManagerForRig(r *rig.Rig, mode Mode) (*Manager, error) {
+	g, err := git.NewBackend(r.GitBackend, r.Path)
+	if err != nil {
+		return nil, fmt.Errorf("selecting git backend: %w", err)
+	}
+	return NewManagerWithMode(r, g, mode), nil
+}
+
 // polecatDir returns the directory for a polecat.
 func (m *Manager) polecatDir(name string) string {
 	return filepath.Join(m.rig.Path, "polecats", name)
@@ -49,7 +71,9 @@ func (m *Manager) exists(name string) bool {
 	return err == nil
 }
 
-// Add creates a new polecat with a clone of the rig.
+// Add creates a new polecat, materializing its working tree according to
+// the manager's mode (a full clone or a worktree off the rig's canonical
+// checkout).
 func (m *Manager) Add(name string) (*Polecat, error) {
 	if m.exists(name) {
 		return nil, ErrPolecatExists
@@ -63,29 +87,50 @@ func (m *Manager) Add(name string) (*Polecat, error) {
 		return nil, fmt.Errorf("creating polecats dir: %w", err)
 	}
 
-	// Clone the rig repo
-	if err := m.git.Clone(m.rig.GitURL, polecatPath); err != nil {
-		return nil, fmt.Errorf("cloning rig: %w", err)
-	}
-
-	// Create working branch
-	polecatGit := git.NewGit(polecatPath)
 	branchName := fmt.Sprintf("polecat/%s", name)
-	if err := polecatGit.CreateBranch(branchName); err != nil {
-		os.RemoveAll(polecatPath)
-		return nil, fmt.Errorf("creating branch: %w", err)
-	}
-	if err := polecatGit.Checkout(branchName); err != nil {
-		os.RemoveAll(polecatPath)
-		return nil, fmt.Errorf("checking out branch: %w", err)
+
+	switch m.mode {
+	case ModeWorktree:
+		if err := m.git.WorktreeAdd(polecatPath, branchName); err != nil {
+			return nil, fmt.Errorf("adding worktree: %w", err)
+		}
+	default:
+		// Clone the rig repo
+		if err := m.git.Clone(m.rig.GitURL, polecatPath); err != nil {
+			return nil, fmt.Errorf("cloning rig: %w", err)
+		}
+
+		// A real Clone creates polecatPath itself, but a memory-backed one
+		// (git.NewGoGitBackendMemory) has no on-disk destination and is a
+		// no-op: state.json still needs somewhere to live, so make sure the
+		// directory exists either way.
+		if err := os.MkdirAll(polecatPath, 0755); err != nil {
+			return nil, fmt.Errorf("creating polecat dir: %w", err)
+		}
+
+		// Create working branch
+		polecatGit, err := m.git.Open(polecatPath)
+		if err != nil {
+			os.RemoveAll(polecatPath)
+			return nil, fmt.Errorf("opening clone: %w", err)
+		}
+		if err := polecatGit.CreateBranch(branchName); err != nil {
+			os.RemoveAll(polecatPath)
+			return nil, fmt.Errorf("creating branch: %w", err)
+		}
+		if err := polecatGit.Checkout(branchName); err != nil {
+			os.RemoveAll(polecatPath)
+			return nil, fmt.Errorf("checking out branch: %w", err)
+		}
 	}
 
 	// Create polecat state
-	now := time.Now()
+	now := m.now()
 	polecat := &Polecat{
 		Name:      name,
 		Rig:       m.rig.Name,
 		State:     StateIdle,
+		Mode:      m.mode,
 		ClonePath: polecatPath,
 		Branch:    branchName,
 		CreatedAt: now,
@@ -94,21 +139,48 @@ func (m *Manager) Add(name string) (*Polecat, error) {
 
 	// Save state
 	if err := m.saveState(polecat); err != nil {
-		os.RemoveAll(polecatPath)
+		if m.mode == ModeWorktree {
+			m.git.WorktreeRemove(polecatPath, true)
+			m.git.WorktreePrune()
+		} else {
+			os.RemoveAll(polecatPath)
+		}
 		return nil, fmt.Errorf("saving state: %w", err)
 	}
 
 	return polecat, nil
 }
 
-// Remove deletes a polecat.
+// Remove deletes a polecat, releasing its clone or worktree.
 func (m *Manager) Remove(name string) error {
 	if !m.exists(name) {
 		return ErrPolecatNotFound
 	}
 
+	polecat, err := m.Get(name)
+	if err != nil {
+		return err
+	}
+
 	polecatPath := m.polecatDir(name)
-	polecatGit := git.NewGit(polecatPath)
+
+	if polecat.Mode == ModeWorktree {
+		if err := m.git.WorktreeRemove(polecatPath, false); err != nil {
+			if isLockedOrDirty(err) {
+				return ErrHasChanges
+			}
+			return fmt.Errorf("removing worktree: %w", err)
+		}
+		if err := m.git.WorktreePrune(); err != nil {
+			return fmt.Errorf("pruning worktrees: %w", err)
+		}
+		return nil
+	}
+
+	polecatGit, err := m.git.Open(polecatPath)
+	if err != nil {
+		return fmt.Errorf("opening clone: %w", err)
+	}
 
 	// Check for uncommitted changes
 	hasChanges, err := polecatGit.HasUncommittedChanges()
@@ -124,6 +196,60 @@ func (m *Manager) Remove(name string) error {
 	return nil
 }
 
+// isLockedOrDirty reports whether err is a git worktree error caused by the
+// worktree being locked or having local modifications.
+func isLockedOrDirty(err error) bool {
+	msg := err.Error()
+	return strings.Contains(msg, "is locked") ||
+		strings.Contains(msg, "locked working tree") ||
+		strings.Contains(msg, "is dirty") ||
+		strings.Contains(msg, "contains modified or untracked files")
+}
+
+// Repair reconciles the rig's registered git worktrees with polecat state
+// files after a crash, force-removing worktrees that no longer have a
+// corresponding polecat and pruning their administrative files.
+func (m *Manager) Repair() error {
+	if m.mode != ModeWorktree {
+		return nil
+	}
+
+	out, err := m.git.WorktreeList()
+	if err != nil {
+		return fmt.Errorf("listing worktrees: %w", err)
+	}
+
+	polecatsDir := filepath.Join(m.rig.Path, "polecats")
+	for _, wtPath := range parseWorktreePaths(out) {
+		if !strings.HasPrefix(wtPath, polecatsDir+string(filepath.Separator)) {
+			continue // not a polecat worktree
+		}
+
+		name := filepath.Base(wtPath)
+		if m.exists(name) {
+			continue
+		}
+
+		if err := m.git.WorktreeRemove(wtPath, true); err != nil {
+			return fmt.Errorf("removing orphaned worktree %s: %w", name, err)
+		}
+	}
+
+	return m.git.WorktreePrune()
+}
+
+// parseWorktreePaths extracts worktree paths from `git worktree list
+// --porcelain` output.
+func parseWorktreePaths(porcelain string) []string {
+	var paths []string
+	for _, line := range strings.Split(porcelain, "\n") {
+		if path, ok := strings.CutPrefix(line, "worktree "); ok {
+			paths = append(paths, path)
+		}
+	}
+	return paths
+}
+
 // List returns all polecats in the rig.
 func (m *Manager) List() ([]*Polecat, error) {
 	polecatsDir := filepath.Join(m.rig.Path, "polecats")
@@ -161,76 +287,109 @@ func (m *Manager) Get(name string) (*Polecat, error) {
 	return m.loadState(name)
 }
 
-// SetState updates a polecat's state.
-func (m *Manager) SetState(name string, state State) error {
-	polecat, err := m.Get(name)
-	if err != nil {
-		return err
-	}
+// AssignIssue assigns an issue to a polecat, transitioning it to working.
+// It is only legal from StateActive.
+func (m *Manager) AssignIssue(name, issue string) error {
+	_, err := m.transition(name, EventAssign, func(p *Polecat) {
+		p.Issue = issue
+	})
+	return err
+}
 
-	polecat.State = state
-	polecat.UpdatedAt = time.Now()
+// ClearIssue clears a polecat's issue assignment, transitioning it back to
+// idle. It is only legal from StateDone.
+func (m *Manager) ClearIssue(name string) error {
+	_, err := m.transition(name, EventClear, func(p *Polecat) {
+		p.Issue = ""
+		p.LastHeartbeat = time.Time{}
+	})
+	return err
+}
 
-	return m.saveState(polecat)
+// Heartbeat stamps name's LastHeartbeat with the current time, so the
+// Reaper doesn't consider it stuck.
+func (m *Manager) Heartbeat(name string) error {
+	_, err := m.mutateLocked(name, func(p *Polecat) error {
+		p.LastHeartbeat = m.now()
+		p.UpdatedAt = p.LastHeartbeat
+		return nil
+	})
+	return err
 }
 
-// AssignIssue assigns an issue to a polecat.
-func (m *Manager) AssignIssue(name, issue string) error {
-	polecat, err := m.Get(name)
-	if err != nil {
-		return err
+// Reaper scans all polecats every interval, transitioning any StateWorking
+// polecat whose heartbeat is older than staleAfter to StateStuck. It runs
+// until ctx is canceled. Daemons should call it once, in its own goroutine,
+// as an opt-in background subsystem.
+func (m *Manager) Reaper(ctx context.Context, interval, staleAfter time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.reapOnce(staleAfter)
+		}
 	}
-
-	polecat.Issue = issue
-	polecat.State = StateWorking
-	polecat.UpdatedAt = time.Now()
-
-	return m.saveState(polecat)
 }
 
-// ClearIssue removes the issue assignment from a polecat.
-func (m *Manager) ClearIssue(name string) error {
-	polecat, err := m.Get(name)
+// reapOnce runs a single reaper scan over all polecats. A polecat is only
+// actually marked stuck if it's still stale at the moment its state.json
+// lock is acquired, so a heartbeat racing the scan can't be clobbered by a
+// stuck transition based on a stale snapshot.
+func (m *Manager) reapOnce(staleAfter time.Duration) {
+	polecats, err := m.List()
 	if err != nil {
-		return err
+		return
 	}
 
-	polecat.Issue = ""
-	polecat.State = StateIdle
-	polecat.UpdatedAt = time.Now()
+	now := m.now()
+	for _, p := range polecats {
+		if p.State != StateWorking || !p.IsStale(now, staleAfter) {
+			continue
+		}
 
-	return m.saveState(polecat)
+		m.transitionIf(p.Name, EventStuck, func(p *Polecat) bool {
+			return p.State == StateWorking && p.IsStale(m.now(), staleAfter)
+		}, nil)
+	}
 }
 
 // Wake transitions a polecat from idle to active.
 func (m *Manager) Wake(name string) error {
-	polecat, err := m.Get(name)
-	if err != nil {
-		return err
-	}
-
-	if polecat.State != StateIdle {
-		return fmt.Errorf("polecat is not idle (state: %s)", polecat.State)
-	}
-
-	return m.SetState(name, StateActive)
+	_, err := m.transition(name, EventWake, nil)
+	return err
 }
 
 // Sleep transitions a polecat from active to idle.
 func (m *Manager) Sleep(name string) error {
-	polecat, err := m.Get(name)
-	if err != nil {
-		return err
-	}
+	_, err := m.transition(name, EventSleep, nil)
+	return err
+}
 
-	if polecat.State != StateActive {
-		return fmt.Errorf("polecat is not active (state: %s)", polecat.State)
-	}
+// MarkDone transitions a working polecat to done.
+func (m *Manager) MarkDone(name string) error {
+	_, err := m.transition(name, EventComplete, nil)
+	return err
+}
 
-	return m.SetState(name, StateIdle)
+// MarkStuck transitions a working polecat to stuck.
+func (m *Manager) MarkStuck(name string) error {
+	_, err := m.transition(name, EventStuck, nil)
+	return err
 }
 
-// saveState persists polecat state to disk.
+// Retry transitions a stuck polecat back to working.
+func (m *Manager) Retry(name string) error {
+	_, err := m.transition(name, EventRetry, nil)
+	return err
+}
+
+// saveState persists polecat state to disk. It writes to a temporary file
+// in the same directory and renames it into place, so a process killed
+// mid-write can never leave a truncated or corrupt state.json.
 func (m *Manager) saveState(polecat *Polecat) error {
 	data, err := json.MarshalIndent(polecat, "", "  ")
 	if err != nil {
@@ -238,20 +397,34 @@ func (m *Manager) saveState(polecat *Polecat) error {
 	}
 
 	stateFile := m.stateFile(polecat.Name)
-	if err := os.WriteFile(stateFile, data, 0644); err != nil {
+	tmpFile := stateFile + ".tmp"
+
+	if err := os.WriteFile(tmpFile, data, 0644); err != nil {
 		return fmt.Errorf("writing state: %w", err)
 	}
+	if err := os.Rename(tmpFile, stateFile); err != nil {
+		return fmt.Errorf("renaming state into place: %w", err)
+	}
 
 	return nil
 }
 
-// loadState reads polecat state from disk.
+// loadState reads polecat state from disk. If state.json is missing but a
+// state.json.tmp is present, saveState was killed after writing the
+// temporary file but before the rename landed; loadState recovers it
+// rather than losing the last write.
 func (m *Manager) loadState(name string) (*Polecat, error) {
 	stateFile := m.stateFile(name)
 
 	data, err := os.ReadFile(stateFile)
 	if err != nil {
-		if os.IsNotExist(err) {
+		if !os.IsNotExist(err) {
+			return nil, fmt.Errorf("reading state: %w", err)
+		}
+
+		tmpFile := stateFile + ".tmp"
+		tmpData, tmpErr := os.ReadFile(tmpFile)
+		if tmpErr != nil {
 			// Return minimal polecat if state file missing
 			return &Polecat{
 				Name:      name,
@@ -260,7 +433,11 @@ func (m *Manager) loadState(name string) (*Polecat, error) {
 				ClonePath: m.polecatDir(name),
 			}, nil
 		}
-		return nil, fmt.Errorf("reading state: %w", err)
+
+		if err := os.Rename(tmpFile, stateFile); err != nil {
+			return nil, fmt.Errorf("recovering state from %s: %w", tmpFile, err)
+		}
+		data = tmpData
 	}
 
 	var polecat Polecat
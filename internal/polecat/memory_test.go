@@ -0,0 +1,63 @@
+package polecat
+
+import (
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// TestManagerInMemoryBackend drives Add, AssignIssue, and Remove entirely
+// against a git.NewGoGitBackendMemory backend, with no real disk I/O or git
+// binary involved for the git side of things — the scenario chunk0-2 asked
+// for. state.json itself still lives on a real temp dir, since Manager's
+// own bookkeeping isn't part of the git.Backend abstraction.
+func TestManagerInMemoryBackend(t *testing.T) {
+	backend, err := git.NewGoGitBackendMemory()
+	if err != nil {
+		t.Fatalf("NewGoGitBackendMemory: %v", err)
+	}
+	if err := backend.SeedCommit("README.md", "hello\n"); err != nil {
+		t.Fatalf("SeedCommit: %v", err)
+	}
+
+	dir := t.TempDir()
+	r := &rig.Rig{Name: "test-rig", Path: dir, GitURL: "https://example.invalid/test-rig.git"}
+	m := NewManager(r, backend)
+
+	p, err := m.Add("p1")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	if p.Mode != ModeClone {
+		t.Errorf("Mode = %q, want %q", p.Mode, ModeClone)
+	}
+	if p.Branch != "polecat/p1" {
+		t.Errorf("Branch = %q, want %q", p.Branch, "polecat/p1")
+	}
+
+	if err := m.Wake("p1"); err != nil {
+		t.Fatalf("Wake: %v", err)
+	}
+	if err := m.AssignIssue("p1", "issue-1"); err != nil {
+		t.Fatalf("AssignIssue: %v", err)
+	}
+
+	got, err := m.Get("p1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.State != StateWorking {
+		t.Errorf("State = %q, want %q", got.State, StateWorking)
+	}
+	if got.Issue != "issue-1" {
+		t.Errorf("Issue = %q, want %q", got.Issue, "issue-1")
+	}
+
+	if err := m.Remove("p1"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+	if m.exists("p1") {
+		t.Error("polecat still exists after Remove")
+	}
+}
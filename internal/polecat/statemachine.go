@@ -0,0 +1,161 @@
+package polecat
+
+import "fmt"
+
+// Event names identify what triggered a state transition. They are passed
+// to transition hooks registered with Manager.OnTransition.
+const (
+	EventWake     = "wake"
+	EventSleep    = "sleep"
+	EventAssign   = "assign"
+	EventComplete = "complete"
+	EventStuck    = "stuck"
+	EventRetry    = "retry"
+	EventClear    = "clear"
+)
+
+// ErrInvalidTransition is returned when an event is not legal from a
+// polecat's current state.
+type ErrInvalidTransition struct {
+	From  State
+	To    State
+	Event string
+}
+
+func (e *ErrInvalidTransition) Error() string {
+	return fmt.Sprintf("invalid transition: %s cannot %s (would go to %s)", e.From, e.Event, e.To)
+}
+
+// transition describes one legal (From, Event) -> To edge.
+type transition struct {
+	From  State
+	Event string
+	To    State
+}
+
+// transitions is the polecat lifecycle's full legal transition table.
+var transitions = []transition{
+	{From: StateIdle, Event: EventWake, To: StateActive},
+	{From: StateActive, Event: EventSleep, To: StateIdle},
+	{From: StateActive, Event: EventAssign, To: StateWorking},
+	{From: StateWorking, Event: EventComplete, To: StateDone},
+	{From: StateWorking, Event: EventStuck, To: StateStuck},
+	{From: StateStuck, Event: EventRetry, To: StateWorking},
+	{From: StateDone, Event: EventClear, To: StateIdle},
+}
+
+// nextState looks up the legal destination for (from, event), if any.
+func nextState(from State, event string) (State, bool) {
+	for _, t := range transitions {
+		if t.From == from && t.Event == event {
+			return t.To, true
+		}
+	}
+	return "", false
+}
+
+// TransitionHook is called after a polecat successfully transitions.
+type TransitionHook func(p *Polecat, from, to State, event string)
+
+// OnTransition registers a hook called after every successful state
+// transition. Hooks run synchronously, in registration order, after the
+// new state has been persisted.
+func (m *Manager) OnTransition(hook TransitionHook) {
+	m.hooks = append(m.hooks, hook)
+}
+
+// transition applies event to the named polecat's state machine, persists
+// the result, and fires registered transition hooks. mutate, if non-nil,
+// is called on the polecat before it is saved so callers can set
+// additional fields (e.g. Issue) atomically with the state change.
+func (m *Manager) transition(name, event string, mutate func(p *Polecat)) (*Polecat, error) {
+	return m.transitionIf(name, event, nil, mutate)
+}
+
+// transitionIf is like transition, but precondition (if non-nil) is
+// evaluated on the freshly loaded, lock-held polecat immediately before the
+// transition is applied; if it returns false, nothing is changed and both
+// the returned polecat and error are nil. This lets callers like the Reaper
+// re-verify a condition (e.g. "heartbeat is still stale") atomically with
+// the state change instead of racing a stale snapshot against it.
+func (m *Manager) transitionIf(name, event string, precondition func(p *Polecat) bool, mutate func(p *Polecat)) (*Polecat, error) {
+	var from, to State
+	var skipped bool
+
+	polecat, err := m.mutateLocked(name, func(p *Polecat) error {
+		if precondition != nil && !precondition(p) {
+			skipped = true
+			return nil
+		}
+
+		from = p.State
+
+		var ok bool
+		to, ok = nextState(from, event)
+		if !ok {
+			// Report where event leads from the states it is legal in.
+			for _, t := range transitions {
+				if t.Event == event {
+					to = t.To
+					break
+				}
+			}
+			return &ErrInvalidTransition{From: from, To: to, Event: event}
+		}
+
+		now := m.now()
+		p.State = to
+		p.LastEvent = event
+		p.LastTransitionAt = now
+		p.UpdatedAt = now
+
+		if mutate != nil {
+			mutate(p)
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	if skipped {
+		return nil, nil
+	}
+
+	for _, hook := range m.hooks {
+		hook(polecat, from, to, event)
+	}
+
+	return polecat, nil
+}
+
+// mutateLocked loads name's polecat state under an advisory lock on
+// state.json.lock, applies mutate, and persists the result — so concurrent
+// callers (e.g. the swarm scheduler and a CLI command) can't race each
+// other into an inconsistent state.json. If mutate returns an error,
+// nothing is persisted.
+func (m *Manager) mutateLocked(name string, mutate func(p *Polecat) error) (*Polecat, error) {
+	if !m.exists(name) {
+		return nil, ErrPolecatNotFound
+	}
+
+	lock, err := m.lockState(name)
+	if err != nil {
+		return nil, err
+	}
+	defer lock.Unlock()
+
+	polecat, err := m.loadState(name)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := mutate(polecat); err != nil {
+		return nil, err
+	}
+
+	if err := m.saveState(polecat); err != nil {
+		return nil, err
+	}
+
+	return polecat, nil
+}
@@ -0,0 +1,130 @@
+package polecat
+
+import (
+	"errors"
+	"os"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// fakeBackend is a no-op git.Backend for tests that only exercise state
+// transitions and never need to touch a real repository.
+type fakeBackend struct{}
+
+func (fakeBackend) Clone(url, dir string) error                  { return nil }
+func (fakeBackend) Open(dir string) (git.Backend, error)         { return fakeBackend{}, nil }
+func (fakeBackend) CreateBranch(name string) error               { return nil }
+func (fakeBackend) Checkout(name string) error                   { return nil }
+func (fakeBackend) HasUncommittedChanges() (bool, error)         { return false, nil }
+func (fakeBackend) Fetch(remote string) error                    { return nil }
+func (fakeBackend) Push(remote, branch string) error             { return nil }
+func (fakeBackend) CurrentSHA() (string, error)                  { return "", nil }
+func (fakeBackend) WorktreeAdd(path, branch string) error        { return nil }
+func (fakeBackend) WorktreeRemove(path string, force bool) error { return nil }
+func (fakeBackend) WorktreePrune() error                         { return nil }
+func (fakeBackend) WorktreeList() (string, error)                { return "", nil }
+
+// newTestManager returns a Manager with a single polecat named "p" seeded
+// directly into state.json, bypassing Add (which requires a real git backend).
+func newTestManager(t *testing.T, state State) *Manager {
+	t.Helper()
+
+	dir := t.TempDir()
+	r := &rig.Rig{Name: "test-rig", Path: dir}
+	m := NewManager(r, fakeBackend{})
+
+	if err := os.MkdirAll(m.polecatDir("p"), 0755); err != nil {
+		t.Fatalf("creating polecat dir: %v", err)
+	}
+
+	p := &Polecat{Name: "p", Rig: r.Name, State: state, ClonePath: m.polecatDir("p")}
+	if err := m.saveState(p); err != nil {
+		t.Fatalf("seeding initial state: %v", err)
+	}
+
+	return m
+}
+
+func TestManagerTransitions(t *testing.T) {
+	cases := []struct {
+		name      string
+		from      State
+		invoke    func(m *Manager) error
+		wantState State
+		wantErr   bool
+	}{
+		{"wake from idle succeeds", StateIdle, func(m *Manager) error { return m.Wake("p") }, StateActive, false},
+		{"wake from active is illegal", StateActive, func(m *Manager) error { return m.Wake("p") }, StateActive, true},
+		{"sleep from active succeeds", StateActive, func(m *Manager) error { return m.Sleep("p") }, StateIdle, false},
+		{"sleep from idle is illegal", StateIdle, func(m *Manager) error { return m.Sleep("p") }, StateIdle, true},
+		{"assign from active succeeds", StateActive, func(m *Manager) error { return m.AssignIssue("p", "issue-1") }, StateWorking, false},
+		{"assign from idle is illegal", StateIdle, func(m *Manager) error { return m.AssignIssue("p", "issue-1") }, StateIdle, true},
+		{"complete from working succeeds", StateWorking, func(m *Manager) error { return m.MarkDone("p") }, StateDone, false},
+		{"complete from idle is illegal", StateIdle, func(m *Manager) error { return m.MarkDone("p") }, StateIdle, true},
+		{"stuck from working succeeds", StateWorking, func(m *Manager) error { return m.MarkStuck("p") }, StateStuck, false},
+		{"retry from stuck succeeds", StateStuck, func(m *Manager) error { return m.Retry("p") }, StateWorking, false},
+		{"retry from working is illegal", StateWorking, func(m *Manager) error { return m.Retry("p") }, StateWorking, true},
+		{"clear from done succeeds", StateDone, func(m *Manager) error { return m.ClearIssue("p") }, StateIdle, false},
+		{"clear from working is illegal", StateWorking, func(m *Manager) error { return m.ClearIssue("p") }, StateWorking, true},
+	}
+
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := newTestManager(t, tc.from)
+
+			err := tc.invoke(m)
+			if tc.wantErr {
+				var invalid *ErrInvalidTransition
+				if !errors.As(err, &invalid) {
+					t.Fatalf("invoke() error = %v, want *ErrInvalidTransition", err)
+				}
+			} else if err != nil {
+				t.Fatalf("invoke() unexpected error: %v", err)
+			}
+
+			got, err := m.Get("p")
+			if err != nil {
+				t.Fatalf("Get: %v", err)
+			}
+			if got.State != tc.wantState {
+				t.Errorf("state = %q, want %q", got.State, tc.wantState)
+			}
+		})
+	}
+}
+
+func TestManagerOnTransition(t *testing.T) {
+	m := newTestManager(t, StateIdle)
+
+	var calls int
+	var gotFrom, gotTo State
+	var gotEvent string
+	m.OnTransition(func(p *Polecat, from, to State, event string) {
+		calls++
+		gotFrom, gotTo, gotEvent = from, to, event
+	})
+
+	if err := m.Wake("p"); err != nil {
+		t.Fatalf("Wake: %v", err)
+	}
+
+	if calls != 1 {
+		t.Fatalf("hook called %d times, want 1", calls)
+	}
+	if gotFrom != StateIdle || gotTo != StateActive || gotEvent != EventWake {
+		t.Errorf("hook saw (%s, %s, %s), want (%s, %s, %s)", gotFrom, gotTo, gotEvent, StateIdle, StateActive, EventWake)
+	}
+
+	p, err := m.Get("p")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if p.LastEvent != EventWake {
+		t.Errorf("LastEvent = %q, want %q", p.LastEvent, EventWake)
+	}
+	if p.LastTransitionAt.IsZero() {
+		t.Error("LastTransitionAt was not recorded")
+	}
+}
@@ -33,6 +33,18 @@ func (s State) IsWorking() bool {
 	return s == StateWorking
 }
 
+// Mode determines how a polecat's working tree is materialized from the rig.
+type Mode string
+
+const (
+	// ModeClone gives the polecat a full clone of the rig's repository.
+	ModeClone Mode = "clone"
+
+	// ModeWorktree gives the polecat a `git worktree` checked out from the
+	// rig's canonical clone, sharing its object database.
+	ModeWorktree Mode = "worktree"
+)
+
 // Polecat represents a worker agent in a rig.
 type Polecat struct {
 	// Name is the polecat identifier.
@@ -44,7 +56,10 @@ type Polecat struct {
 	// State is the current lifecycle state.
 	State State `json:"state"`
 
-	// ClonePath is the path to the polecat's clone of the rig.
+	// Mode is how ClonePath was materialized (clone or worktree).
+	Mode Mode `json:"mode,omitempty"`
+
+	// ClonePath is the path to the polecat's working tree (a clone or a worktree).
 	ClonePath string `json:"clone_path"`
 
 	// Branch is the current git branch.
@@ -53,6 +68,18 @@ type Polecat struct {
 	// Issue is the currently assigned issue ID (if any).
 	Issue string `json:"issue,omitempty"`
 
+	// LastHeartbeat is when the polecat last reported itself alive via
+	// Manager.Heartbeat. Zero means it has not reported one since it was
+	// created or since its issue was last cleared by Manager.ClearIssue.
+	LastHeartbeat time.Time `json:"last_heartbeat,omitempty"`
+
+	// LastEvent is the name of the state-machine event that produced the
+	// current State.
+	LastEvent string `json:"last_event,omitempty"`
+
+	// LastTransitionAt is when LastEvent was applied.
+	LastTransitionAt time.Time `json:"last_transition_at,omitempty"`
+
 	// CreatedAt is when the polecat was created.
 	CreatedAt time.Time `json:"created_at"`
 
@@ -75,3 +102,13 @@ func (p *Polecat) Summary() Summary {
 		Issue: p.Issue,
 	}
 }
+
+// IsStale reports whether the polecat has a heartbeat and it is older than
+// staleAfter relative to now. A polecat that has never reported a
+// heartbeat is never stale.
+func (p *Polecat) IsStale(now time.Time, staleAfter time.Duration) bool {
+	if p.LastHeartbeat.IsZero() {
+		return false
+	}
+	return now.Sub(p.LastHeartbeat) > staleAfter
+}
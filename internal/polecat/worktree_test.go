@@ -0,0 +1,206 @@
+package polecat
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/steveyegge/gastown/internal/git"
+	"github.com/steveyegge/gastown/internal/rig"
+)
+
+// fakeWorktreeBackend simulates just enough of `git worktree` administration
+// to drive Manager.Add/Remove/Repair in ModeWorktree without a real
+// repository or the git binary: it tracks registered worktree paths and lets
+// tests mark one locked/dirty to exercise the ErrHasChanges mapping.
+type fakeWorktreeBackend struct {
+	mu            sync.Mutex
+	worktrees     map[string]string // path -> branch
+	lockedOrDirty map[string]bool
+	pruned        int
+}
+
+func newFakeWorktreeBackend() *fakeWorktreeBackend {
+	return &fakeWorktreeBackend{
+		worktrees:     map[string]string{},
+		lockedOrDirty: map[string]bool{},
+	}
+}
+
+func (b *fakeWorktreeBackend) Clone(url, dir string) error          { return nil }
+func (b *fakeWorktreeBackend) Open(dir string) (git.Backend, error) { return b, nil }
+func (b *fakeWorktreeBackend) CreateBranch(name string) error       { return nil }
+func (b *fakeWorktreeBackend) Checkout(name string) error           { return nil }
+func (b *fakeWorktreeBackend) HasUncommittedChanges() (bool, error) { return false, nil }
+func (b *fakeWorktreeBackend) Fetch(remote string) error            { return nil }
+func (b *fakeWorktreeBackend) Push(remote, branch string) error     { return nil }
+func (b *fakeWorktreeBackend) CurrentSHA() (string, error)          { return "", nil }
+
+func (b *fakeWorktreeBackend) WorktreeAdd(path, branch string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return err
+	}
+	b.worktrees[path] = branch
+	return nil
+}
+
+func (b *fakeWorktreeBackend) WorktreeRemove(path string, force bool) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.lockedOrDirty[path] && !force {
+		return fmt.Errorf("fatal: '%s' contains modified or untracked files, use --force to delete it", path)
+	}
+
+	delete(b.worktrees, path)
+	delete(b.lockedOrDirty, path)
+	return os.RemoveAll(path)
+}
+
+func (b *fakeWorktreeBackend) WorktreePrune() error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.pruned++
+	return nil
+}
+
+func (b *fakeWorktreeBackend) WorktreeList() (string, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var sb strings.Builder
+	for path, branch := range b.worktrees {
+		fmt.Fprintf(&sb, "worktree %s\nHEAD 0000000000000000000000000000000000000000\nbranch refs/heads/%s\n\n", path, branch)
+	}
+	return sb.String(), nil
+}
+
+func newWorktreeTestManager(t *testing.T) (*Manager, *fakeWorktreeBackend) {
+	t.Helper()
+
+	dir := t.TempDir()
+	r := &rig.Rig{Name: "test-rig", Path: dir, GitURL: "https://example.invalid/test-rig.git"}
+	backend := newFakeWorktreeBackend()
+	return NewManagerWithMode(r, backend, ModeWorktree), backend
+}
+
+func TestManagerAddWorktreeMode(t *testing.T) {
+	m, backend := newWorktreeTestManager(t)
+
+	p, err := m.Add("p1")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	if p.Mode != ModeWorktree {
+		t.Errorf("Mode = %q, want %q", p.Mode, ModeWorktree)
+	}
+	if p.Branch != "polecat/p1" {
+		t.Errorf("Branch = %q, want %q", p.Branch, "polecat/p1")
+	}
+	if branch, ok := backend.worktrees[p.ClonePath]; !ok || branch != "polecat/p1" {
+		t.Errorf("backend has no worktree registered at %s (got %q, ok=%v)", p.ClonePath, branch, ok)
+	}
+
+	got, err := m.Get("p1")
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if got.Mode != ModeWorktree {
+		t.Errorf("persisted Mode = %q, want %q (mixed clone/worktree rigs need this in state.json)", got.Mode, ModeWorktree)
+	}
+}
+
+func TestManagerRemoveWorktreeMode(t *testing.T) {
+	m, backend := newWorktreeTestManager(t)
+
+	p, err := m.Add("p1")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+
+	if err := m.Remove("p1"); err != nil {
+		t.Fatalf("Remove: %v", err)
+	}
+
+	if m.exists("p1") {
+		t.Error("polecat still exists after Remove")
+	}
+	if _, ok := backend.worktrees[p.ClonePath]; ok {
+		t.Error("worktree still registered with the backend after Remove")
+	}
+	if backend.pruned != 1 {
+		t.Errorf("WorktreePrune called %d times, want 1", backend.pruned)
+	}
+}
+
+func TestManagerRemoveWorktreeMode_LockedOrDirtyMapsToErrHasChanges(t *testing.T) {
+	m, backend := newWorktreeTestManager(t)
+
+	p, err := m.Add("p1")
+	if err != nil {
+		t.Fatalf("Add: %v", err)
+	}
+	backend.lockedOrDirty[p.ClonePath] = true
+
+	if err := m.Remove("p1"); err != ErrHasChanges {
+		t.Fatalf("Remove error = %v, want ErrHasChanges", err)
+	}
+
+	if !m.exists("p1") {
+		t.Error("polecat was removed despite the worktree being locked/dirty")
+	}
+	if backend.pruned != 0 {
+		t.Errorf("WorktreePrune called %d times, want 0 (Remove should bail out before pruning)", backend.pruned)
+	}
+}
+
+func TestManagerRepairRemovesOrphanedWorktrees(t *testing.T) {
+	m, backend := newWorktreeTestManager(t)
+
+	if _, err := m.Add("p1"); err != nil {
+		t.Fatalf("Add(p1): %v", err)
+	}
+
+	// Simulate a crash that left git's worktree administration pointing at a
+	// polecat directory that no longer exists (e.g. something removed it
+	// without going through Manager.Remove): register the worktree with the
+	// backend directly, without creating polecats/orphan on disk.
+	orphanPath := filepath.Join(m.rig.Path, "polecats", "orphan")
+	backend.worktrees[orphanPath] = "polecat/orphan"
+
+	if err := m.Repair(); err != nil {
+		t.Fatalf("Repair: %v", err)
+	}
+
+	if _, ok := backend.worktrees[orphanPath]; ok {
+		t.Error("orphaned worktree was not removed by Repair")
+	}
+	if _, ok := backend.worktrees[filepath.Join(m.rig.Path, "polecats", "p1")]; !ok {
+		t.Error("Repair removed a worktree that still has a polecat")
+	}
+	if backend.pruned != 1 {
+		t.Errorf("WorktreePrune called %d times, want 1", backend.pruned)
+	}
+}
+
+func TestManagerRepairIsNoOpInCloneMode(t *testing.T) {
+	dir := t.TempDir()
+	r := &rig.Rig{Name: "test-rig", Path: dir}
+	backend := newFakeWorktreeBackend()
+	m := NewManagerWithMode(r, backend, ModeClone)
+
+	if err := m.Repair(); err != nil {
+		t.Fatalf("Repair: %v", err)
+	}
+	if backend.pruned != 0 {
+		t.Errorf("WorktreePrune called %d times in ModeClone, want 0", backend.pruned)
+	}
+}
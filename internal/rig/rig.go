@@ -0,0 +1,20 @@
+// Package rig describes the repositories that polecats and swarms operate on.
+package rig
+
+import "github.com/steveyegge/gastown/internal/git"
+
+// Rig represents a managed repository that polecats clone or worktree from.
+type Rig struct {
+	// Name is the rig identifier.
+	Name string
+
+	// Path is the canonical on-disk checkout of the rig.
+	Path string
+
+	// GitURL is the remote URL polecats clone from.
+	GitURL string
+
+	// GitBackend selects which git.Backend implementation polecats for this
+	// rig use. Empty defaults to git.KindExec.
+	GitBackend git.Kind
+}